@@ -0,0 +1,117 @@
+package desec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const baseURL = "https://desec.io/api/v1/"
+
+// httpClient bounds how long a single deSEC API call can take. Without a
+// timeout, a connection that's accepted but never answered would hang
+// forever and the retry/backoff logic in request() would never get a
+// chance to run.
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// api is the handle used to talk to the deSEC REST API.
+type api struct {
+	creds struct {
+		token string
+	}
+	domainIndex  map[string]bool
+	domainKeys   map[string][]dnsKey
+	bulkUpdates  bool
+	dnssecStrict bool
+	maxRetries   int
+}
+
+// resourceRecord mirrors deSEC's rrset JSON representation.
+type resourceRecord struct {
+	Subname string   `json:"subname"`
+	Type    string   `json:"type"`
+	Records []string `json:"records"`
+	TTL     int      `json:"ttl"`
+}
+
+// domainObject mirrors deSEC's domain JSON representation.
+type domainObject struct {
+	Name string   `json:"name"`
+	Keys []dnsKey `json:"keys,omitempty"`
+}
+
+// doOnce performs a single deSEC API call, marshaling body (if any) as the
+// request payload. It returns the raw response so the caller (request, which
+// adds retry/backoff) can decide whether the call should be retried.
+func (c *api) doOnce(method, endpoint string, body interface{}) (*http.Response, error) {
+	var reqBody []byte
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = b
+	}
+
+	req, err := http.NewRequest(method, baseURL+endpoint, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Token "+c.creds.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	return httpClient.Do(req)
+}
+
+func (c *api) fetchDomainList() error {
+	var domains []domainObject
+	if err := c.request("GET", "domains/", nil, &domains); err != nil {
+		return err
+	}
+	c.domainIndex = map[string]bool{}
+	c.domainKeys = map[string][]dnsKey{}
+	for _, d := range domains {
+		c.domainIndex[d.Name] = true
+		c.domainKeys[d.Name] = d.Keys
+	}
+	return nil
+}
+
+func (c *api) createDomain(domain string) error {
+	var result domainObject
+	if err := c.request("POST", "domains/", domainObject{Name: domain}, &result); err != nil {
+		return err
+	}
+	c.domainIndex[domain] = true
+	c.domainKeys[domain] = result.Keys
+	return nil
+}
+
+func (c *api) getRecords(domain string) ([]resourceRecord, error) {
+	var recs []resourceRecord
+	err := c.request("GET", fmt.Sprintf("domains/%s/rrsets/", domain), nil, &recs)
+	return recs, err
+}
+
+func (c *api) upsertRR(rr resourceRecord, domain string) error {
+	endpoint := fmt.Sprintf("domains/%s/rrsets/%s/%s/", domain, rr.Subname, rr.Type)
+	body := struct {
+		TTL     int      `json:"ttl"`
+		Records []string `json:"records"`
+	}{rr.TTL, rr.Records}
+	return c.request("PUT", endpoint, body, nil)
+}
+
+func (c *api) deleteRR(rr resourceRecord, domain string) error {
+	endpoint := fmt.Sprintf("domains/%s/rrsets/%s/%s/", domain, rr.Subname, rr.Type)
+	return c.request("DELETE", endpoint, nil, nil)
+}
+
+// patchRRsets sends a single bulk PATCH of rrsets to domains/{domain}/rrsets/.
+// deSEC applies the whole batch atomically: either every rrset in it is
+// applied, or none are.
+func (c *api) patchRRsets(domain string, rrsets []resourceRecord) error {
+	return c.request("PATCH", fmt.Sprintf("domains/%s/rrsets/", domain), rrsets, nil)
+}
@@ -0,0 +1,79 @@
+package desec
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/miekg/dns/dnsutil"
+)
+
+// maxBulkRRsetsPerPatch caps how many rrsets we pack into a single PATCH to
+// domains/{name}/rrsets/, per deSEC's documented per-request size limit.
+const maxBulkRRsetsPerPatch = 500
+
+// generateBulkCorrections is the "bulk-updates" code path: instead of one
+// upsertRR/deleteRR HTTP call per changed label, it gathers every upsert and
+// delete for dc into one (or, for very large changesets, a handful of)
+// PATCH(es) to domains/{name}/rrsets/. Each PATCH is applied atomically by
+// deSEC.
+func (client *api) generateBulkCorrections(dc *models.DomainConfig, keysToUpdate map[models.RecordKey][]string, desiredRecords map[models.RecordKey]models.Records) ([]*models.Correction, error) {
+	var rrsets []resourceRecord
+	var allMsgs []string
+
+	for label, msgs := range keysToUpdate {
+		allMsgs = append(allMsgs, msgs...)
+		if recs, ok := desiredRecords[label]; ok {
+			rrsets = append(rrsets, recordsToNative(recs, dc.Name)...)
+			continue
+		}
+		// Not present in the desired state: delete, expressed as an empty
+		// records list on the rrset. deSEC's rrset schema enforces a TTL
+		// minimum even here, so this still needs a valid TTL, not the zero
+		// value - unlike the bodyless DELETE deleteRR uses outside bulk mode.
+		shortname := dnsutil.TrimDomainName(label.NameFQDN, dc.Name)
+		if shortname == "@" {
+			shortname = ""
+		}
+		rrsets = append(rrsets, resourceRecord{
+			Subname: shortname,
+			Type:    label.Type,
+			TTL:     minTTL,
+			Records: []string{},
+		})
+	}
+
+	batches := batchRRsets(rrsets, maxBulkRRsetsPerPatch)
+	corrections := make([]*models.Correction, 0, len(batches))
+	for i, batch := range batches {
+		batch := batch
+		corrections = append(corrections, &models.Correction{
+			Msg: fmt.Sprintf("bulk PATCH %d/%d: %d rrset(s)", i+1, len(batches), len(batch)),
+			F: func() error {
+				return client.patchRRsets(dc.Name, batch)
+			},
+		})
+	}
+	// Fold the per-label change descriptions into the first correction's
+	// message so `dnscontrol push` still reports what's changing, not just
+	// a batch count.
+	if len(corrections) > 0 {
+		corrections[0].Msg = strings.Join(allMsgs, "\n") + "\n" + corrections[0].Msg
+	}
+	return corrections, nil
+}
+
+// batchRRsets splits rrsets into ordered chunks of at most max entries, so
+// zones with more changes than deSEC's per-request limit still get applied,
+// one atomic PATCH per chunk.
+func batchRRsets(rrsets []resourceRecord, max int) [][]resourceRecord {
+	if len(rrsets) == 0 {
+		return nil
+	}
+	var batches [][]resourceRecord
+	for len(rrsets) > max {
+		batches = append(batches, rrsets[:max])
+		rrsets = rrsets[max:]
+	}
+	return append(batches, rrsets)
+}
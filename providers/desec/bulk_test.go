@@ -0,0 +1,80 @@
+package desec
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func TestBatchRRsets(t *testing.T) {
+	rr := func(n int) []resourceRecord {
+		out := make([]resourceRecord, n)
+		for i := range out {
+			out[i] = resourceRecord{Subname: "", Type: "A"}
+		}
+		return out
+	}
+
+	tests := []struct {
+		name      string
+		in        []resourceRecord
+		max       int
+		wantBatch int
+		wantSizes []int
+	}{
+		{"empty input", nil, 500, 0, nil},
+		{"under max", rr(3), 500, 1, []int{3}},
+		{"exact multiple of max", rr(1000), 500, 2, []int{500, 500}},
+		{"max plus one", rr(501), 500, 2, []int{500, 1}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := batchRRsets(tc.in, tc.max)
+			if len(got) != tc.wantBatch {
+				t.Fatalf("expected %d batches, got %d", tc.wantBatch, len(got))
+			}
+			for i, batch := range got {
+				if len(batch) != tc.wantSizes[i] {
+					t.Errorf("batch %d: expected %d rrsets, got %d", i, tc.wantSizes[i], len(batch))
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateBulkCorrections(t *testing.T) {
+	domain := "example.com"
+	dc := &models.DomainConfig{Name: domain}
+
+	upsertKey := models.RecordKey{NameFQDN: "www." + domain, Type: "A"}
+	deleteKey := models.RecordKey{NameFQDN: "old." + domain, Type: "A"}
+
+	keysToUpdate := map[models.RecordKey][]string{
+		upsertKey: {"+ www.example.com A 1.2.3.4"},
+		deleteKey: {"- old.example.com A 5.6.7.8"},
+	}
+	desiredRecords := map[models.RecordKey]models.Records{
+		upsertKey: {mustRecord(t, "www", domain, "A", "1.2.3.4", 3600)},
+	}
+
+	client := &api{}
+	corrections, err := client.generateBulkCorrections(dc, keysToUpdate, desiredRecords)
+	if err != nil {
+		t.Fatalf("generateBulkCorrections: %v", err)
+	}
+	if len(corrections) != 1 {
+		t.Fatalf("expected a single batched correction, got %d", len(corrections))
+	}
+	for _, msg := range keysToUpdate[upsertKey] {
+		if !strings.Contains(corrections[0].Msg, msg) {
+			t.Errorf("expected correction message to contain %q, got %q", msg, corrections[0].Msg)
+		}
+	}
+	for _, msg := range keysToUpdate[deleteKey] {
+		if !strings.Contains(corrections[0].Msg, msg) {
+			t.Errorf("expected correction message to contain %q, got %q", msg, corrections[0].Msg)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package desec
+
+import (
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+	"github.com/miekg/dns/dnsutil"
+)
+
+// nativeToRecords converts a single deSEC rrset (which may carry several
+// rdata values) into dnscontrol's one-record-per-value representation.
+func nativeToRecords(rr resourceRecord, origin string) []*models.RecordConfig {
+	results := make([]*models.RecordConfig, 0, len(rr.Records))
+	for _, value := range rr.Records {
+		rc := &models.RecordConfig{
+			Type: rr.Type,
+			TTL:  uint32(rr.TTL),
+		}
+		label := rr.Subname
+		if label == "" {
+			label = "@"
+		}
+		rc.SetLabel(label, origin)
+		if err := rc.PopulateFromString(rr.Type, value, origin); err != nil {
+			printer.Warnf("unparsable record received from deSEC: %v\n", err)
+			continue
+		}
+		results = append(results, rc)
+	}
+	return results
+}
+
+// recordsToNative converts the dnscontrol records sharing a single
+// label:type key into the single deSEC rrset needed to represent them.
+// deSEC models an rrset as {subname,type,ttl,records:[...]} with every
+// rdata value for that label:type living together in one Records slice, so
+// labels with several records of the same type (multiple MX, TXT, CAA, ...)
+// merge into one rrset rather than one rrset per record.
+func recordsToNative(recs models.Records, domain string) []resourceRecord {
+	if len(recs) == 0 {
+		return nil
+	}
+	shortname := dnsutil.TrimDomainName(recs[0].GetLabelFQDN(), domain)
+	if shortname == "@" {
+		shortname = ""
+	}
+	rr := resourceRecord{
+		Subname: shortname,
+		Type:    recs[0].Type,
+		TTL:     int(recs[0].TTL),
+		Records: make([]string, 0, len(recs)),
+	}
+	for _, rec := range recs {
+		rr.Records = append(rr.Records, rec.GetTargetCombined())
+	}
+	return []resourceRecord{rr}
+}
@@ -0,0 +1,49 @@
+package desec
+
+import (
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func mustRecord(t *testing.T, label, domain, rtype, target string, ttl uint32) *models.RecordConfig {
+	t.Helper()
+	rc := &models.RecordConfig{Type: rtype, TTL: ttl}
+	rc.SetLabel(label, domain)
+	if err := rc.PopulateFromString(rtype, target, domain); err != nil {
+		t.Fatalf("PopulateFromString(%s, %s): %v", rtype, target, err)
+	}
+	return rc
+}
+
+func TestRecordsToNativeMergesMultiValueRRsets(t *testing.T) {
+	domain := "example.com"
+
+	tests := []struct {
+		name    string
+		label   string
+		rtype   string
+		targets []string
+	}{
+		{"multi-value MX", "@", "MX", []string{"10 mail1.example.com.", "20 mail2.example.com."}},
+		{"multi-value TXT", "_dmarc", "TXT", []string{`"v=spf1 include:_spf.example.com ~all"`, `"some-other-txt-value"`}},
+		{"multi-value CAA", "@", "CAA", []string{`0 issue "letsencrypt.org"`, `0 issuewild ";"`}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var recs models.Records
+			for _, target := range tc.targets {
+				recs = append(recs, mustRecord(t, tc.label, domain, tc.rtype, target, 3600))
+			}
+
+			native := recordsToNative(recs, domain)
+			if len(native) != 1 {
+				t.Fatalf("expected a single merged rrset, got %d", len(native))
+			}
+			if got := len(native[0].Records); got != len(tc.targets) {
+				t.Fatalf("expected %d records in merged rrset, got %d", len(tc.targets), got)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package desec
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/StackExchange/dnscontrol/v3/models"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff"
@@ -15,6 +16,13 @@ import (
 desec API DNS provider:
 Info required in `creds.json`:
    - auth-token
+   - bulk-updates (optional): "true" to PATCH all of a zone's changes in one
+     (or a few, if the zone is large) atomic request instead of one HTTP call
+     per changed label.
+   - dnssec-enforce (optional): "true" to error (instead of warn) when a
+     domain's dnsconfig.js sets AutoDNSSEC to "off", which deSEC cannot honor.
+   - max-retries (optional): how many times to retry a request that deSEC
+     throttled with a 429/503 before giving up. Defaults to 5.
 */
 
 // NewDeSec creates the provider.
@@ -24,6 +32,16 @@ func NewDeSec(m map[string]string, metadata json.RawMessage) (providers.DNSServi
 	if c.creds.token == "" {
 		return nil, fmt.Errorf("missing deSEC auth-token")
 	}
+	c.bulkUpdates = m["bulk-updates"] == "true"
+	c.dnssecStrict = m["dnssec-enforce"] == "true"
+	c.maxRetries = defaultMaxRetries
+	if v := m["max-retries"]; v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max-retries %q: %w", v, err)
+		}
+		c.maxRetries = n
+	}
 
 	// Get a domain to validate authentication
 	if err := c.fetchDomainList(); err != nil {
@@ -44,9 +62,12 @@ var features = providers.DocumentationNotes{
 	providers.CanUseTLSA:             providers.Can(),
 	providers.CanUsePTR:              providers.Unimplemented(),
 	providers.CanGetZones:            providers.Can(),
-	providers.CanAutoDNSSEC:          providers.Cannot(),
+	providers.CanAutoDNSSEC:          providers.Can(),
 }
 
+// minTTL is the lowest TTL deSEC accepts on a record (NS excepted).
+const minTTL = 3600
+
 var defaultNameServerNames = []string{
 	"ns1.desec.io",
 	"ns2.desec.org",
@@ -62,6 +83,12 @@ func (c *api) GetNameservers(domain string) ([]*models.Nameserver, error) {
 }
 
 func (c *api) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	if err := c.checkAutoDNSSEC(dc); err != nil {
+		return nil, err
+	}
+	if err := c.printDSRecords(dc); err != nil {
+		return nil, err
+	}
 	existing, err := c.GetZoneRecords(dc.Name)
 	if err != nil {
 		return nil, err
@@ -122,11 +149,11 @@ func PrepDesiredRecords(dc *models.DomainConfig) {
 			printer.Warnf("deSEC does not support alias records\n")
 			continue
 		}
-		if rec.TTL < 3600 {
+		if rec.TTL < minTTL {
 			if rec.Type != "NS" {
-				printer.Warnf("deSEC does not support ttls < 3600. Setting ttl of %s type %s from %d to 3600\n", rec.GetLabelFQDN(), rec.Type, rec.TTL)
+				printer.Warnf("deSEC does not support ttls < %d. Setting ttl of %s type %s from %d to %d\n", minTTL, rec.GetLabelFQDN(), rec.Type, rec.TTL, minTTL)
 			}
-			rec.TTL = 3600
+			rec.TTL = minTTL
 		}
 		recordsToKeep = append(recordsToKeep, rec)
 	}
@@ -153,6 +180,10 @@ func (client *api) GenerateDomainCorrections(dc *models.DomainConfig, existing m
 	desiredRecords := dc.Records.GroupedByKey()
 	//doesLabelExist := existing.FQDNMap()
 
+	if client.bulkUpdates {
+		return client.generateBulkCorrections(dc, keysToUpdate, desiredRecords)
+	}
+
 	// For any key with an update, delete or replace those records.
 	for label := range keysToUpdate {
 		if _, ok := desiredRecords[label]; !ok {
@@ -195,10 +226,8 @@ func (client *api) GenerateDomainCorrections(dc *models.DomainConfig, existing m
 			}
 		} else {
 			//it must be an update or create, both can be done with the same api call.
+			//recordsToNative merges every record for this label:type into a single rrset.
 			ns := recordsToNative(desiredRecords[label], dc.Name)
-			if len(ns) > 1 {
-				panic("we got more than one resource record to create / modify")
-			}
 			for i, msg := range keysToUpdate[label] {
 				if i == 0 {
 					corrections = append(corrections,
@@ -0,0 +1,74 @@
+package desec
+
+import (
+	"fmt"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+)
+
+// dnsKey mirrors one entry of deSEC's per-domain "keys" field: the
+// DNSKEY/DS material deSEC generated for that zone's automatic signing.
+type dnsKey struct {
+	DNSKEY  string   `json:"dnskey"`
+	DS      []string `json:"ds"`
+	Flags   int      `json:"flags"`
+	KeyType string   `json:"keytype"`
+}
+
+// GetDomainDNSKeys returns the DS records deSEC published for domain's
+// automatically-managed signing keys. These must be handed to the parent
+// zone / registrar to complete the chain of trust.
+//
+// It reads from the cache fetchDomainList populated rather than issuing a
+// live request: fetchDomainList already runs when the provider is created
+// (in NewDeSec), and again in EnsureDomainExists, so the cache is filled
+// before corrections are generated, and a second round trip here would only
+// add to deSEC's rate-limit pressure.
+func (c *api) GetDomainDNSKeys(domain string) ([]models.DNSSECKey, error) {
+	var keys []models.DNSSECKey
+	for _, k := range c.domainKeys[domain] {
+		for _, ds := range k.DS {
+			keys = append(keys, models.DNSSECKey{DS: ds})
+		}
+	}
+	return keys, nil
+}
+
+// checkAutoDNSSEC warns (or, with dnssec-enforce set in creds.json, errors)
+// when dc asks to turn signing off: deSEC signs every zone automatically
+// and has no way to disable it.
+func (c *api) checkAutoDNSSEC(dc *models.DomainConfig) error {
+	if dc.AutoDNSSEC != "off" {
+		return nil
+	}
+	msg := fmt.Sprintf("deSEC signs all zones automatically; AutoDNSSEC: off has no effect for %s", dc.Name)
+	if c.dnssecStrict {
+		return fmt.Errorf(msg)
+	}
+	printer.Warnf(msg + "\n")
+	return nil
+}
+
+// printDSRecords prints the DS records that must be published at dc's
+// registrar to complete the chain of trust. deSEC generates and rotates
+// these independently of any pending record change (a zone-creation run can
+// race deSEC's async key generation, and a key rollover may have no
+// accompanying record diff at all), so this is informational output, not a
+// models.Correction: it must stay visible on every run, including an
+// otherwise no-op one, without inflating the correction count that
+// push/CI treat as "something to apply".
+func (c *api) printDSRecords(dc *models.DomainConfig) error {
+	keys, err := c.GetDomainDNSKeys(dc.Name)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	printer.Printf("Publish these DS records for %s at your registrar:\n", dc.Name)
+	for _, k := range keys {
+		printer.Printf("  %s\n", k.DS)
+	}
+	return nil
+}
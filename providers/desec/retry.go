@@ -0,0 +1,91 @@
+package desec
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// defaultMaxRetries is how many times we retry a 429/503 before giving up,
+// unless overridden by "max-retries" in creds.json.
+const defaultMaxRetries = 5
+
+// maxTotalRetryWait caps how long a single request() call will sleep across
+// all of its retries combined, so a misbehaving server can't hang
+// `dnscontrol push` indefinitely.
+const maxTotalRetryWait = 2 * time.Minute
+
+// throttleDetailSeconds matches the number of seconds out of deSEC's
+// "Request was throttled. Expected available in N seconds." detail message,
+// used as a fallback when no Retry-After header is present.
+var throttleDetailSeconds = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*seconds?`)
+
+// request performs a deSEC API call, marshaling body (if any) as the
+// request payload and unmarshaling the response into target (if non-nil).
+// On a 429 or 503 it parses Retry-After (or, failing that, the throttle
+// detail in the response body), sleeps with a little jitter, and retries up
+// to maxRetries times.
+func (c *api) request(method, endpoint string, body, target interface{}) error {
+	var totalWait time.Duration
+	for attempt := 0; ; attempt++ {
+		resp, err := c.doOnce(method, endpoint, body)
+		if err != nil {
+			return err
+		}
+		respBody, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		if (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) && attempt < c.maxRetries {
+			wait := retryAfterDelay(resp.Header.Get("Retry-After"), respBody)
+			totalWait += wait
+			if totalWait > maxTotalRetryWait {
+				return fmt.Errorf("deSEC API error (%s %s): gave up after %s of throttling", method, endpoint, totalWait.Round(time.Second))
+			}
+			time.Sleep(wait)
+			continue
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("deSEC API error (%s %s): %d: %s", method, endpoint, resp.StatusCode, string(respBody))
+		}
+		if target != nil && len(respBody) > 0 {
+			return json.Unmarshal(respBody, target)
+		}
+		return nil
+	}
+}
+
+// retryAfterDelay parses a Retry-After header (delta-seconds, or an
+// HTTP-date per RFC 7231 §7.1.3) and falls back to deSEC's JSON throttle
+// detail when the header is absent. A little jitter is added so a burst of
+// throttled calls doesn't all retry in lockstep.
+func retryAfterDelay(header string, body []byte) time.Duration {
+	wait := time.Second
+	switch {
+	case header != "":
+		if secs, err := strconv.Atoi(header); err == nil {
+			wait = time.Duration(secs) * time.Second
+		} else if when, err := http.ParseTime(header); err == nil {
+			wait = time.Until(when)
+		}
+	default:
+		if m := throttleDetailSeconds.FindSubmatch(body); m != nil {
+			if secs, err := strconv.ParseFloat(string(m[1]), 64); err == nil {
+				wait = time.Duration(secs * float64(time.Second))
+			}
+		}
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	jitter := time.Duration(rand.Int63n(int64(250 * time.Millisecond)))
+	return wait + jitter
+}
@@ -0,0 +1,59 @@
+package desec
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryAfterDelay(t *testing.T) {
+	const jitterCeiling = 250 * time.Millisecond
+
+	tests := []struct {
+		name    string
+		header  string
+		body    []byte
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{
+			name:    "delta-seconds header",
+			header:  "5",
+			wantMin: 5 * time.Second,
+			wantMax: 5*time.Second + jitterCeiling,
+		},
+		{
+			name:    "HTTP-date header",
+			header:  time.Now().Add(3 * time.Second).UTC().Format(http.TimeFormat),
+			wantMin: 2 * time.Second, // allow for clock/formatting slop
+			wantMax: 3*time.Second + jitterCeiling,
+		},
+		{
+			name:    "no header, JSON throttle detail",
+			body:    []byte(`{"detail":"Request was throttled. Expected available in 7 seconds."}`),
+			wantMin: 7 * time.Second,
+			wantMax: 7*time.Second + jitterCeiling,
+		},
+		{
+			name:    "no header, no parseable body",
+			body:    []byte(`{"detail":"nope"}`),
+			wantMin: 1 * time.Second,
+			wantMax: 1*time.Second + jitterCeiling,
+		},
+		{
+			name:    "malformed header falls back to default",
+			header:  "not-a-number-or-date",
+			wantMin: 1 * time.Second,
+			wantMax: 1*time.Second + jitterCeiling,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := retryAfterDelay(tc.header, tc.body)
+			if got < tc.wantMin || got > tc.wantMax {
+				t.Errorf("retryAfterDelay(%q, %q) = %s, want between %s and %s", tc.header, tc.body, got, tc.wantMin, tc.wantMax)
+			}
+		})
+	}
+}